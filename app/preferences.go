@@ -2,15 +2,26 @@ package app
 
 import (
 	"encoding/json"
-	"os"
-	"path/filepath"
+	"fmt"
+	"image/color"
 	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/internal"
+	"fyne.io/fyne/v2/theme"
 )
 
+// themeOverridesPreferenceKey is the reserved preferences key used to persist colors and
+// sizes customised at runtime, for example through a theme editor.
+const themeOverridesPreferenceKey = "_theme_overrides"
+
+// themeOverrides is the JSON shape stored under themeOverridesPreferenceKey.
+type themeOverrides struct {
+	Colors map[fyne.ThemeColorName]string `json:"colors,omitempty"`
+	Sizes  map[fyne.SizeName]float32      `json:"sizes,omitempty"`
+}
+
 type preferences struct {
 	*internal.InMemoryPreferences
 
@@ -27,12 +38,112 @@ type preferences struct {
 	suspendChange       bool
 	numSuspendedChanges int
 
+	storage  PreferencesStorage
+	syncMode SyncMode
+	stopSync func()
+
 	app *fyneApp
 }
 
+// SyncMode controls how a Preferences reacts to its backing storage changing outside of its
+// own Save calls, for example another instance of the app, cloud sync, or a settings editor.
+//
+// Since: 2.6
+type SyncMode int
+
+const (
+	// SyncOff disables watching the backing storage for external changes.
+	//
+	// Since: 2.6
+	SyncOff SyncMode = iota
+	// SyncReadOnly watches the backing storage and reloads when it changes externally, but
+	// does not write this instance's own changes back out.
+	//
+	// Since: 2.6
+	SyncReadOnly
+	// SyncReadWrite watches the backing storage and reloads when it changes externally, as
+	// well as writing this instance's own changes out as usual. This is the default.
+	//
+	// Since: 2.6
+	SyncReadWrite
+)
+
 // Declare conformity with Preferences interface
 var _ fyne.Preferences = (*preferences)(nil)
 
+// SetPreferencesStorage configures the backend used to persist a's preferences, replacing
+// the default JSON-on-disk storage. It must be called before the app's preferences are
+// first read or written.
+//
+// Since: 2.6
+func SetPreferencesStorage(a fyne.App, storage PreferencesStorage) {
+	p, ok := a.Preferences().(*preferences)
+	if !ok {
+		return
+	}
+
+	p.prefLock.Lock()
+	p.storage = storage
+	p.prefLock.Unlock()
+}
+
+// SetPreferencesSyncMode changes how a's preferences react to its backing storage changing
+// outside of its own Save calls, see SyncMode.
+//
+// Since: 2.6
+func SetPreferencesSyncMode(a fyne.App, mode SyncMode) {
+	p, ok := a.Preferences().(*preferences)
+	if !ok {
+		return
+	}
+
+	p.startSync(mode)
+}
+
+// startSync (re)starts watching the backing storage according to mode, stopping any
+// previous watch first.
+func (p *preferences) startSync(mode SyncMode) {
+	p.prefLock.Lock()
+	p.syncMode = mode
+	stop := p.stopSync
+	p.stopSync = nil
+	storage := p.storage
+	p.prefLock.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+	if mode == SyncOff {
+		return
+	}
+
+	stop, err := storage.Watch(p.onExternalChange)
+	if err != nil {
+		fyne.LogError("Failed to watch preferences for external changes", err)
+		return
+	}
+
+	p.prefLock.Lock()
+	p.stopSync = stop
+	p.prefLock.Unlock()
+}
+
+// onExternalChange is called when the backing storage changes for a reason other than our
+// own Save, so the in-memory values and any listeners stay up to date.
+func (p *preferences) onExternalChange() {
+	p.prefLock.Lock()
+	p.suspendChange = true
+	p.prefLock.Unlock()
+
+	p.load()
+
+	p.prefLock.Lock()
+	p.suspendChange = false
+	p.prefLock.Unlock()
+
+	p.InMemoryPreferences.FireChange()
+}
+
 func (p *preferences) resetSuspend() {
 	go func() {
 		time.Sleep(time.Millisecond * 100) // writes are not always atomic. 10ms worked, 100 is safer.
@@ -49,87 +160,51 @@ func (p *preferences) resetSuspend() {
 }
 
 func (p *preferences) save() error {
-	return p.saveToFile(p.storagePath())
-}
-
-func (p *preferences) saveToFile(path string) error {
 	p.prefLock.Lock()
 	p.suspendChange = true
+	storage := p.storage
 	p.prefLock.Unlock()
 	defer p.resetSuspend()
-	err := os.MkdirAll(filepath.Dir(path), 0700)
-	if err != nil { // this is not an exists error according to docs
-		return err
-	}
 
-	file, err := os.Create(path)
-	if err != nil {
-		if !os.IsExist(err) {
-			return err
-		}
-		file, err = os.Open(path) // #nosec
-		if err != nil {
-			return err
-		}
-	}
-	defer file.Close()
-	encode := json.NewEncoder(file)
-
-	p.InMemoryPreferences.ReadValues(func(values map[string]interface{}) {
-		err = encode.Encode(&values)
+	var values map[string]interface{}
+	p.InMemoryPreferences.ReadValues(func(v map[string]interface{}) {
+		values = v
 	})
 
-	err2 := file.Sync()
-	if err == nil {
-		err = err2
-	}
-	return err
+	return storage.Save(values)
 }
 
 func (p *preferences) load() {
-	err := p.loadFromFile(p.storagePath())
-	if err != nil {
-		fyne.LogError("Preferences load error:", err)
-	}
-}
+	p.prefLock.RLock()
+	storage := p.storage
+	p.prefLock.RUnlock()
 
-func (p *preferences) loadFromFile(path string) (err error) {
-	file, err := os.Open(path) // #nosec
+	values, err := storage.Load()
 	if err != nil {
-		if os.IsNotExist(err) {
-			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
-				return err
-			}
-			return nil
-		}
-		return err
+		fyne.LogError("Preferences load error:", err)
+		return
 	}
-	defer func() {
-		if r := file.Close(); r != nil && err == nil {
-			err = r
-		}
-	}()
-	decode := json.NewDecoder(file)
 
 	p.prefLock.Lock()
 	p.loadingInProgress = true
 	p.prefLock.Unlock()
 
-	p.InMemoryPreferences.WriteValues(func(values map[string]interface{}) {
-		err = decode.Decode(&values)
+	p.InMemoryPreferences.WriteValues(func(v map[string]interface{}) {
+		for key, value := range values {
+			v[key] = value
+		}
 	})
 
 	p.prefLock.Lock()
 	p.loadingInProgress = false
 	p.prefLock.Unlock()
-
-	return err
 }
 
 func newPreferences(app *fyneApp) *preferences {
 	p := &preferences{}
 	p.app = app
 	p.InMemoryPreferences = internal.NewInMemoryPreferences()
+	p.storage = NewCodecStorage(NewFileStorage(p.storagePath()), JSONCodec{})
 
 	// don't load or watch if not setup
 	if app.uniqueID == "" {
@@ -138,13 +213,13 @@ func newPreferences(app *fyneApp) *preferences {
 
 	p.AddChangeListener(func() {
 		p.prefLock.Lock()
-		shouldIgnoreChange := p.suspendChange || p.loadingInProgress
+		shouldIgnoreChange := p.suspendChange || p.loadingInProgress || p.syncMode == SyncReadOnly
 		if p.suspendChange {
 			p.numSuspendedChanges++
 		}
 		p.prefLock.Unlock()
 
-		if shouldIgnoreChange { // callback after loading file, or too many updates in a row
+		if shouldIgnoreChange { // callback after loading file, too many updates in a row, or read-only sync
 			return
 		}
 
@@ -153,6 +228,75 @@ func newPreferences(app *fyneApp) *preferences {
 			fyne.LogError("Failed on saving preferences", err)
 		}
 	})
-	p.watch()
+	p.load()
+	p.startSync(SyncReadWrite)
+	p.applyThemeOverrides()
 	return p
 }
+
+// applyThemeOverrides re-hydrates a theme.OverlayTheme from the persisted
+// themeOverridesPreferenceKey, if any overrides were saved, and pushes it onto the app so
+// that a theme editor's changes survive restart.
+func (p *preferences) applyThemeOverrides() {
+	raw := p.String(themeOverridesPreferenceKey)
+	if raw == "" {
+		return
+	}
+
+	var overrides themeOverrides
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		fyne.LogError("Failed to parse persisted theme overrides", err)
+		return
+	}
+
+	overlay := theme.NewOverlayTheme(p.app.Settings().Theme())
+	for name, hex := range overrides.Colors {
+		c, err := parseHexColor(hex)
+		if err != nil {
+			fyne.LogError("Failed to parse persisted theme color override", err)
+			continue
+		}
+		overlay.SetColor(name, c)
+	}
+	for name, size := range overrides.Sizes {
+		overlay.SetSize(name, size)
+	}
+
+	p.app.Settings().SetTheme(overlay)
+}
+
+// saveThemeOverrides persists the given overrides under themeOverridesPreferenceKey, so a
+// theme editor's changes survive restart.
+func (p *preferences) saveThemeOverrides(overrides themeOverrides) error {
+	data, err := json.Marshal(&overrides)
+	if err != nil {
+		return err
+	}
+
+	p.SetString(themeOverridesPreferenceKey, string(data))
+	return nil
+}
+
+// parseHexColor parses the "#rrggbb" and "#rrggbbaa" forms written by saveThemeOverrides.
+// The length is checked up front rather than trying "#rrggbb" first and falling back - Sscanf
+// happily matches the first 6 hex digits of an 8-digit string and silently drops the alpha
+// byte instead of erroring into the longer pattern.
+func parseHexColor(hex string) (color.Color, error) {
+	var r, g, b, a uint8
+	a = 0xff
+
+	switch len(hex) {
+	case 7: // #rrggbb
+		if _, err := fmt.Sscanf(hex, "#%02x%02x%02x", &r, &g, &b); err != nil {
+			return nil, err
+		}
+	case 9: // #rrggbbaa
+		if _, err := fmt.Sscanf(hex, "#%02x%02x%02x%02x", &r, &g, &b, &a); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("app: invalid hex color %q", hex)
+	}
+
+	return color.NRGBA{R: r, G: g, B: b, A: a}, nil
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package app
+
+import "syscall"
+
+// processAlive reports whether pid still refers to a running process. It is used to decide
+// whether a lock file left behind by another instance of the app is stale (that instance has
+// exited) or just slow (e.g. writing to a network/cloud-synced folder).
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	// Signal 0 does no actual signalling, it only checks that the process exists and that we
+	// are allowed to signal it. Success or EPERM means it exists; any other error (normally
+	// ESRCH) means it is gone.
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
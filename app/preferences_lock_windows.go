@@ -0,0 +1,13 @@
+//go:build windows
+
+package app
+
+// processAlive reports whether pid still refers to a running process.
+//
+// There is no cheap dependency-free way to check this on Windows, so we conservatively
+// assume the owning process is still alive. A lock file left behind by another process is
+// therefore never taken over; lock gives up with an error once it has waited longer than
+// staleLockTimeout, which bounds how long a crashed process can block others.
+func processAlive(pid int) bool {
+	return true
+}
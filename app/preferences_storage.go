@@ -0,0 +1,396 @@
+package app
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PreferencesStorage is the backend used to persist a Preferences' values.
+// The default implementation stores JSON on the local filesystem, but apps that need a
+// different format or location can provide their own through app.SetPreferencesStorage.
+//
+// Since: 2.6
+type PreferencesStorage interface {
+	// Load reads back the full set of stored values, or an empty map if nothing has been
+	// saved yet.
+	Load() (map[string]interface{}, error)
+	// Save persists the full set of values, replacing whatever was previously stored.
+	Save(map[string]interface{}) error
+	// Watch starts monitoring the storage for changes made outside of Save, invoking the
+	// given callback whenever one is seen. It returns a stop function that must be called
+	// to release any resources, and must be safe to call more than once.
+	Watch(func()) (stop func(), err error)
+}
+
+// Codec converts a set of preference values to and from a storage-ready byte encoding, so a
+// PreferencesStorage can be reused across different serialisation formats.
+//
+// Since: 2.6
+type Codec interface {
+	Encode(map[string]interface{}) ([]byte, error)
+	Decode([]byte) (map[string]interface{}, error)
+}
+
+// JSONCodec is the default Codec, used by the built-in file storage.
+//
+// Since: 2.6
+type JSONCodec struct{}
+
+// Encode implements Codec.
+func (JSONCodec) Encode(values map[string]interface{}) ([]byte, error) {
+	return json.Marshal(values)
+}
+
+// Decode implements Codec.
+func (JSONCodec) Decode(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if len(data) == 0 {
+		return values, nil
+	}
+
+	err := json.Unmarshal(data, &values)
+	return values, err
+}
+
+// ByteStorage is the lower-level primitive that codecStorage and the encrypted wrapper
+// build on - it reads and writes a raw encoded payload without knowing anything about its
+// format. Use NewFileStorage, wrap it with NewEncryptedStorage if needed, and hand the
+// result to NewCodecStorage to build a full PreferencesStorage.
+//
+// Since: 2.6
+type ByteStorage interface {
+	readAll() ([]byte, error)
+	writeAll([]byte) error
+	watch(func()) (stop func(), err error)
+}
+
+// fileStorage is the default ByteStorage, writing to a single file on the local filesystem.
+// Saves are atomic (write to a temp file then rename) and a sibling lock file is held for
+// the duration of both Load and Save so that two instances of the same app don't clobber
+// each other's preferences.
+type fileStorage struct {
+	path string
+
+	hashLock sync.Mutex
+	lastHash [sha256.Size]byte
+}
+
+// NewFileStorage returns the default ByteStorage, writing to a single file on the local
+// filesystem with atomic, cross-process-locked writes. Wrap it with NewEncryptedStorage for
+// at-rest encryption, then pass the result to NewCodecStorage to build a PreferencesStorage.
+//
+// Since: 2.6
+func NewFileStorage(path string) ByteStorage {
+	return &fileStorage{path: path}
+}
+
+func (f *fileStorage) readAll() ([]byte, error) {
+	unlock, err := f.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(f.path) // #nosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Seed lastHash from whatever is on disk right now, so a watch started after this read
+	// doesn't mistake the file's existing content for an external change on its first poll.
+	f.hashLock.Lock()
+	f.lastHash = sha256.Sum256(data)
+	f.hashLock.Unlock()
+	return data, nil
+}
+
+func (f *fileStorage) writeAll(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) // #nosec
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+
+	f.hashLock.Lock()
+	f.lastHash = sha256.Sum256(data)
+	f.hashLock.Unlock()
+	return nil
+}
+
+// staleLockTimeout bounds how long we wait for a lock held by a process we can't confirm is
+// dead (see processAlive) before giving up, rather than blocking forever. It is a var rather
+// than a const so tests can shrink it.
+var staleLockTimeout = 10 * time.Second
+
+// lock takes a best-effort, cross-process advisory lock using a sidecar file, so that two
+// instances of an app don't read a half-written file or interleave their writes. It is
+// implemented with a plain exclusive-create file rather than flock/LockFileEx so that it
+// behaves the same on every platform this tree targets. The lock file records the owning
+// process's pid so a waiter only ever steals a lock once it has confirmed the owner is
+// actually gone, and unlock only removes the file if it still holds our own pid - a slow
+// holder (e.g. writing to a cloud-synced folder) is never treated as dead just because
+// another instance got impatient.
+func (f *fileStorage) lock() (unlock func(), err error) {
+	lockPath := f.path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0700); err != nil {
+		return nil, err
+	}
+
+	token := strconv.Itoa(os.Getpid())
+	start := time.Now()
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600) // #nosec
+		if err == nil {
+			_, werr := file.WriteString(token)
+			file.Close()
+			if werr != nil {
+				os.Remove(lockPath)
+				return nil, werr
+			}
+			return func() { f.unlock(lockPath, token) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if owner, ok := readLockOwner(lockPath); ok && !processAlive(owner) {
+			// The process that created this lock has exited without cleaning up - it's
+			// safe to take over.
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Since(start) > staleLockTimeout {
+			return nil, fmt.Errorf("app: timed out waiting for preferences lock %s", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// unlock removes the lock file only if it still records our own token, so we never delete a
+// lock that another instance has since (legitimately) taken over.
+func (f *fileStorage) unlock(lockPath, token string) {
+	if owner, ok := readLockOwner(lockPath); !ok || strconv.Itoa(owner) != token {
+		return
+	}
+	os.Remove(lockPath)
+}
+
+func readLockOwner(lockPath string) (pid int, ok bool) {
+	data, err := os.ReadFile(lockPath) // #nosec
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err = strconv.Atoi(string(data))
+	return pid, err == nil
+}
+
+// watchDebounce is the interval at which watch polls the preferences file. Writes that land
+// within one interval are seen as a single change; a burst spanning several polls triggers
+// one reload for each poll that sees new content.
+const watchDebounce = 200 * time.Millisecond
+
+// watch notifies fn whenever the preferences file changes on disk for a reason other than
+// our own writeAll - for example another instance of the app, or cloud sync. It polls the
+// file's content hash rather than depending on a native filesystem-watcher package, so it
+// behaves the same on every platform this tree targets, including sandboxes (e.g. mobile)
+// that don't support inotify/FSEvents.
+func (f *fileStorage) watch(fn func()) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	ticker := time.NewTicker(watchDebounce)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				f.notifyIfChanged(fn)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(stop)
+		})
+	}, nil
+}
+
+// notifyIfChanged re-reads the file and calls fn only if its content differs from the hash
+// recorded by our own last writeAll, so we don't react to changes we made ourselves.
+func (f *fileStorage) notifyIfChanged(fn func()) {
+	data, err := os.ReadFile(f.path) // #nosec
+	if err != nil {
+		return
+	}
+
+	hash := sha256.Sum256(data)
+
+	f.hashLock.Lock()
+	unchanged := hash == f.lastHash
+	f.lastHash = hash
+	f.hashLock.Unlock()
+
+	if unchanged {
+		return
+	}
+
+	fn()
+}
+
+// codecStorage adapts a ByteStorage and a Codec into a PreferencesStorage.
+type codecStorage struct {
+	bytes ByteStorage
+	codec Codec
+}
+
+// NewCodecStorage adapts a ByteStorage and a Codec into a PreferencesStorage, so embedded or
+// IoT builds can pick a smaller format than the default JSONCodec.
+//
+// Since: 2.6
+func NewCodecStorage(bytes ByteStorage, codec Codec) PreferencesStorage {
+	return &codecStorage{bytes: bytes, codec: codec}
+}
+
+func (c *codecStorage) Load() (map[string]interface{}, error) {
+	data, err := c.bytes.readAll()
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	return c.codec.Decode(data)
+}
+
+func (c *codecStorage) Save(values map[string]interface{}) error {
+	data, err := c.codec.Encode(values)
+	if err != nil {
+		return err
+	}
+
+	return c.bytes.writeAll(data)
+}
+
+func (c *codecStorage) Watch(fn func()) (func(), error) {
+	return c.bytes.watch(fn)
+}
+
+// KeySource supplies the symmetric key used by an encrypted ByteStorage, for example one
+// backed by the OS keychain. It is called once per Load/Save.
+//
+// Since: 2.6
+type KeySource func() ([32]byte, error)
+
+// encryptedByteStorage wraps another ByteStorage and encrypts its payload with AES-GCM,
+// using a key obtained from the given KeySource.
+type encryptedByteStorage struct {
+	inner ByteStorage
+	key   KeySource
+}
+
+// NewEncryptedStorage wraps another ByteStorage and encrypts its payload with AES-GCM, using
+// a key obtained from the given KeySource - for example one backed by the OS keychain.
+//
+// Since: 2.6
+func NewEncryptedStorage(inner ByteStorage, key KeySource) ByteStorage {
+	return &encryptedByteStorage{inner: inner, key: key}
+}
+
+func (e *encryptedByteStorage) readAll() ([]byte, error) {
+	data, err := e.inner.readAll()
+	if err != nil || len(data) == 0 {
+		return data, err
+	}
+
+	gcm, err := e.cipher()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("app: encrypted preferences payload is too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (e *encryptedByteStorage) writeAll(data []byte) error {
+	gcm, err := e.cipher()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	return e.inner.writeAll(gcm.Seal(nonce, nonce, data, nil))
+}
+
+func (e *encryptedByteStorage) watch(fn func()) (func(), error) {
+	return e.inner.watch(fn)
+}
+
+func (e *encryptedByteStorage) cipher() (cipher.AEAD, error) {
+	key, err := e.key()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
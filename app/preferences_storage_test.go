@@ -0,0 +1,171 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFileStorage_AtomicRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	storage := NewCodecStorage(NewFileStorage(path), JSONCodec{})
+
+	values := map[string]interface{}{"greeting": "hello", "count": float64(3)}
+	if err := storage.Save(values); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected temp file to be renamed away, stat err = %v", err)
+	}
+
+	loaded, err := storage.Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded["greeting"] != "hello" || loaded["count"] != float64(3) {
+		t.Fatalf("loaded values don't match what was saved: %#v", loaded)
+	}
+}
+
+func TestFileStorage_LockStealsOnlyFromDeadOwner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("processAlive can't confirm a dead pid on windows")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prefs.json")
+	lockPath := path + ".lock"
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to spawn helper process: %v", err)
+	}
+	deadPID := cmd.Process.Pid
+
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(deadPID)), 0600); err != nil {
+		t.Fatalf("failed to seed stale lock file: %v", err)
+	}
+
+	fs := &fileStorage{path: path}
+	done := make(chan error, 1)
+	go func() {
+		unlock, err := fs.lock()
+		if err == nil {
+			unlock()
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("lock() should have stolen the stale lock, got error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("lock() did not steal a lock left by a dead process within 2s")
+	}
+}
+
+func TestFileStorage_LockWaitsForLiveOwner(t *testing.T) {
+	old := staleLockTimeout
+	staleLockTimeout = 50 * time.Millisecond
+	defer func() { staleLockTimeout = old }()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "prefs.json")
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		t.Fatalf("failed to seed live lock file: %v", err)
+	}
+
+	fs := &fileStorage{path: path}
+	if _, err := fs.lock(); err == nil {
+		t.Fatal("lock() should not steal a lock owned by a live process")
+	}
+}
+
+func TestEncryptedByteStorage_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.bin")
+	key := KeySource(func() ([32]byte, error) {
+		var k [32]byte
+		copy(k[:], "0123456789abcdef0123456789abcdef")
+		return k, nil
+	})
+
+	encrypted := NewEncryptedStorage(NewFileStorage(path), key)
+	storage := NewCodecStorage(encrypted, JSONCodec{})
+
+	values := map[string]interface{}{"secret": "shh"}
+	if err := storage.Save(values); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read encrypted file: %v", err)
+	}
+	if string(raw) == `{"secret":"shh"}` {
+		t.Fatal("payload on disk was not encrypted")
+	}
+
+	loaded, err := storage.Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded["secret"] != "shh" {
+		t.Fatalf("decrypted values don't match what was saved: %#v", loaded)
+	}
+}
+
+func TestFileStorage_NotifyIfChangedIgnoresOwnWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	fs := &fileStorage{path: path}
+
+	if err := fs.writeAll([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("writeAll returned error: %v", err)
+	}
+
+	calls := 0
+	fs.notifyIfChanged(func() { calls++ })
+	if calls != 0 {
+		t.Fatalf("notifyIfChanged should ignore a change matching our own last write, got %d calls", calls)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"a":2}`), 0600); err != nil {
+		t.Fatalf("failed to simulate an external write: %v", err)
+	}
+	fs.notifyIfChanged(func() { calls++ })
+	if calls != 1 {
+		t.Fatalf("notifyIfChanged should fire once for a genuinely external change, got %d calls", calls)
+	}
+
+	fs.notifyIfChanged(func() { calls++ })
+	if calls != 1 {
+		t.Fatalf("notifyIfChanged should not fire again for the same content, got %d calls", calls)
+	}
+}
+
+func TestFileStorage_ReadAllSeedsHashForPreExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0600); err != nil {
+		t.Fatalf("failed to seed a pre-existing preferences file: %v", err)
+	}
+
+	// A fresh fileStorage, as newPreferences builds on every launch, reading back a file that
+	// already existed before this instance started watching it.
+	fs := &fileStorage{path: path}
+	if _, err := fs.readAll(); err != nil {
+		t.Fatalf("readAll returned error: %v", err)
+	}
+
+	calls := 0
+	fs.notifyIfChanged(func() { calls++ })
+	if calls != 0 {
+		t.Fatalf("notifyIfChanged should not treat a file's pre-existing content as a change on the first poll after Load, got %d calls", calls)
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"encoding/json"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/internal"
+)
+
+// newTestPreferences builds a preferences backed by a real file storage, without going
+// through newPreferences - which needs a full fyne.App - so load/save can be tested on
+// their own.
+func newTestPreferences(t *testing.T) *preferences {
+	path := filepath.Join(t.TempDir(), "prefs.json")
+	p := &preferences{}
+	p.InMemoryPreferences = internal.NewInMemoryPreferences()
+	p.storage = NewCodecStorage(NewFileStorage(path), JSONCodec{})
+	return p
+}
+
+func TestPreferences_LoadReadsBackPreviouslySavedValues(t *testing.T) {
+	p := newTestPreferences(t)
+	p.SetString("greeting", "hello")
+	if err := p.save(); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+
+	fresh := &preferences{}
+	fresh.InMemoryPreferences = internal.NewInMemoryPreferences()
+	fresh.storage = p.storage
+
+	fresh.load()
+	if got := fresh.String("greeting"); got != "hello" {
+		t.Fatalf("expected load to hydrate values saved by a previous instance, got %q", got)
+	}
+}
+
+func TestPreferences_LoadDoesNotTriggerASave(t *testing.T) {
+	p := newTestPreferences(t)
+	p.SetString("greeting", "hello")
+	if err := p.save(); err != nil {
+		t.Fatalf("save returned error: %v", err)
+	}
+
+	fresh := &preferences{}
+	fresh.InMemoryPreferences = internal.NewInMemoryPreferences()
+	fresh.storage = p.storage
+	saved := false
+	fresh.AddChangeListener(func() {
+		fresh.prefLock.RLock()
+		ignore := fresh.loadingInProgress
+		fresh.prefLock.RUnlock()
+		if !ignore {
+			saved = true
+		}
+	})
+
+	fresh.load()
+	if saved {
+		t.Fatal("load should mark loadingInProgress so the change listener doesn't re-save what it just read")
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := parseHexColor("#112233")
+	if err != nil {
+		t.Fatalf("parseHexColor returned error for a 6-digit hex string: %v", err)
+	}
+	if want := (color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0xff}); c != want {
+		t.Fatalf("parseHexColor(#112233) = %#v, want %#v", c, want)
+	}
+
+	c, err = parseHexColor("#11223344")
+	if err != nil {
+		t.Fatalf("parseHexColor returned error for an 8-digit hex string: %v", err)
+	}
+	if want := (color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x44}); c != want {
+		t.Fatalf("parseHexColor(#11223344) = %#v, want %#v - alpha byte must not be dropped", c, want)
+	}
+
+	if _, err := parseHexColor("#1122"); err == nil {
+		t.Fatal("parseHexColor should reject a string that is neither 6 nor 8 hex digits")
+	}
+}
+
+func TestPreferences_ThemeOverridesRoundTrip(t *testing.T) {
+	p := newTestPreferences(t)
+
+	overrides := themeOverrides{
+		Colors: map[fyne.ThemeColorName]string{fyne.ThemeColorName("background"): "#11223344"},
+		Sizes:  map[fyne.SizeName]float32{fyne.SizeName("text"): 18},
+	}
+	if err := p.saveThemeOverrides(overrides); err != nil {
+		t.Fatalf("saveThemeOverrides returned error: %v", err)
+	}
+
+	raw := p.String(themeOverridesPreferenceKey)
+	var got themeOverrides
+	if err := json.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("failed to unmarshal persisted theme overrides: %v", err)
+	}
+
+	if got.Colors[fyne.ThemeColorName("background")] != "#11223344" {
+		t.Fatalf("expected the saved color override to round-trip through preferences, got %#v", got.Colors)
+	}
+	if got.Sizes[fyne.SizeName("text")] != 18 {
+		t.Fatalf("expected the saved size override to round-trip through preferences, got %#v", got.Sizes)
+	}
+}
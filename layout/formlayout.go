@@ -3,6 +3,7 @@ package layout
 import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/theme"
 )
 
@@ -11,137 +12,259 @@ const formLayoutCols = 2
 // Declare conformity with Layout interface
 var _ fyne.Layout = (*formLayout)(nil)
 
-// formLayout is two column grid where each row has a label and a widget.
+// formLayout is a table of one or more label/content column pairs, one pair per logical
+// "row" unless pairsPerRow is greater than one, in which case several pairs share a row so
+// a wide window can show them side by side. When sectioned is set, a FormSectionHeader
+// object breaks the current row and is rendered spanning every column.
 type formLayout struct {
+	pairsPerRow int
+	sectioned   bool
 }
 
-func (f *formLayout) countRows(objects []fyne.CanvasObject) int {
-	count := 0
+// formSectionHeader is implemented by objects created with NewFormSectionHeader.
+type formSectionHeader interface {
+	fyne.CanvasObject
+	formSectionHeader()
+}
+
+// FormSectionHeader is a heading that spans every column of a layout.NewSectionedFormLayout,
+// drawn with a top divider and the theme's heading text style.
+//
+// Since: 2.6
+type FormSectionHeader struct {
+	*fyne.Container
+}
+
+// NewFormSectionHeader creates a label that can be placed in a layout.NewSectionedFormLayout
+// to visually group the rows that follow it.
+//
+// Since: 2.6
+func NewFormSectionHeader(label string) *FormSectionHeader {
+	text := canvas.NewText(label, theme.Color(theme.ColorNameForeground))
+	style := theme.Style(theme.StyleNameHeading)
+	text.TextStyle = fyne.TextStyle{
+		Bold:   style&theme.StyleBold != 0,
+		Italic: style&theme.StyleItalic != 0,
+	}
+
+	divider := canvas.NewRectangle(theme.Color(theme.ColorNameSeparator))
+	divider.SetMinSize(fyne.NewSize(1, 1))
+
+	return &FormSectionHeader{Container: container.NewVBox(divider, text)}
+}
+
+func (f *FormSectionHeader) formSectionHeader() {}
+
+// Declare conformity with CanvasObject interface
+var _ fyne.CanvasObject = (*FormSectionHeader)(nil)
+
+// formRow is one laid out row of the table: either a full-width section header or up to
+// pairsPerRow label/content pairs.
+type formRow struct {
+	header fyne.CanvasObject
+	pairs  [][2]fyne.CanvasObject
+}
+
+// splitRows groups the flat list of child objects into formRows, starting a new row
+// whenever a section header is seen (for a sectioned layout) or once pairsPerRow pairs
+// have been collected for the current row.
+func (f *formLayout) splitRows(objects []fyne.CanvasObject) []formRow {
+	var rows []formRow
+	current := formRow{}
 
-	for i := 0; i < len(objects); i += formLayoutCols {
-		if !objects[i].Visible() && !objects[i+1].Visible() {
+	flush := func() {
+		if current.header != nil || len(current.pairs) > 0 {
+			rows = append(rows, current)
+		}
+		current = formRow{}
+	}
+
+	for i := 0; i < len(objects); {
+		if f.sectioned {
+			if header, ok := objects[i].(formSectionHeader); ok {
+				flush()
+				if header.Visible() {
+					rows = append(rows, formRow{header: objects[i]})
+				}
+				i++
+				continue
+			}
+		}
+
+		if i+1 >= len(objects) {
+			break
+		}
+		label, content := objects[i], objects[i+1]
+		i += formLayoutCols
+
+		if !label.Visible() && !content.Visible() {
 			continue
 		}
-		count++
+
+		current.pairs = append(current.pairs, [2]fyne.CanvasObject{label, content})
+		if len(current.pairs) == f.pairsPerRow {
+			flush()
+		}
 	}
+	flush()
 
-	return count
+	return rows
 }
 
-// tableCellsSize defines the size for all the cells of the form table.
-// The height of each row will be set as the max value between the label and content cell heights.
-// The width of the label column will be set as the max width value between all the label cells.
-// The width of the content column will be set as the max width value between all the content cells
-// or the remaining space of the bounding containerWidth, if it is larger.
-func (f *formLayout) tableCellsSize(objects []fyne.CanvasObject, containerWidth float32) (float32, float32, [][2]fyne.Size) {
-	rows := f.countRows(objects)
-	table := make([][2]fyne.Size, rows)
-
-	if (len(objects))%formLayoutCols != 0 {
-		return 0, 0, table
-	}
+// measure lays out the rows and works out the width of every label/content column and the
+// height of every row, stretching the content columns to fill containerWidth if it is wider
+// than the table naturally needs.
+func (f *formLayout) measure(objects []fyne.CanvasObject, containerWidth float32) (rows []formRow, rowHeights []float32, labelWidths, contentWidths []float32) {
+	rows = f.splitRows(objects)
+	rowHeights = make([]float32, len(rows))
+	labelWidths = make([]float32, f.pairsPerRow)
+	contentWidths = make([]float32, f.pairsPerRow)
 
 	padding := theme.Padding()
 	innerPadding := theme.InnerPadding()
-	lowBound := 0
-	highBound := 2
-	labelCellMaxWidth := float32(0)
-	contentCellMaxWidth := float32(0)
-	for row := 0; row < rows; {
-		currentRow := objects[lowBound:highBound]
-		lowBound = highBound
-		highBound += formLayoutCols
-		if !currentRow[0].Visible() && !currentRow[1].Visible() {
-			continue
-		}
 
-		labelCell := currentRow[0].MinSize()
-		if _, ok := currentRow[0].(*canvas.Text); ok {
-			labelCell.Width += innerPadding * 2
+	cellWidth := func(obj fyne.CanvasObject) float32 {
+		width := obj.MinSize().Width
+		if _, ok := obj.(*canvas.Text); ok {
+			width += innerPadding * 2
 		}
-		labelCellMaxWidth = fyne.Max(labelCellMaxWidth, labelCell.Width)
+		return width
+	}
 
-		contentCell := currentRow[1].MinSize()
-		contentCellMaxWidth = fyne.Max(contentCellMaxWidth, contentCell.Width)
+	for r, row := range rows {
+		if row.header != nil {
+			rowHeights[r] = row.header.MinSize().Height + innerPadding*2
+			continue
+		}
 
-		rowHeight := fyne.Max(labelCell.Height, contentCell.Height)
+		height := float32(0)
+		for col, pair := range row.pairs {
+			labelWidths[col] = fyne.Max(labelWidths[col], cellWidth(pair[0]))
+			contentWidths[col] = fyne.Max(contentWidths[col], cellWidth(pair[1]))
+			height = fyne.Max(height, fyne.Max(pair[0].MinSize().Height, pair[1].MinSize().Height))
+		}
+		rowHeights[r] = height
+	}
 
-		labelCell.Height = rowHeight
-		contentCell.Height = rowHeight
+	naturalWidth := float32(0)
+	for col := 0; col < f.pairsPerRow; col++ {
+		naturalWidth += labelWidths[col] + padding + contentWidths[col]
+		if col > 0 {
+			naturalWidth += padding
+		}
+	}
 
-		table[row][0] = labelCell
-		table[row][1] = contentCell
-		row++
+	if extra := containerWidth - naturalWidth; extra > 0 && f.pairsPerRow > 0 {
+		perColumn := extra / float32(f.pairsPerRow)
+		for col := 0; col < f.pairsPerRow; col++ {
+			contentWidths[col] += perColumn
+		}
 	}
 
-	contentCellMaxWidth = fyne.Max(contentCellMaxWidth, containerWidth-labelCellMaxWidth-padding)
-	return labelCellMaxWidth, contentCellMaxWidth, table
+	return rows, rowHeights, labelWidths, contentWidths
 }
 
-// Layout is called to pack all child objects into a table format with two columns.
+// Layout is called to pack all child objects into the form table.
 func (f *formLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
-	labelWidth, contentWidth, table := f.tableCellsSize(objects, size.Width)
+	rows, rowHeights, labelWidths, contentWidths := f.measure(objects, size.Width)
 
 	padding := theme.Padding()
 	innerPadding := theme.InnerPadding()
 
-	row := 0
-	y := float32(0)
-	for i := 0; i < len(objects); i += formLayoutCols {
-		if !objects[i].Visible() && (i+1 < len(objects) && !objects[i+1].Visible()) {
-			continue
+	placeCell := func(obj fyne.CanvasObject, x, y, width, height float32) {
+		if _, ok := obj.(*canvas.Text); ok {
+			obj.Move(fyne.NewPos(x+innerPadding, y+innerPadding))
+			obj.Resize(fyne.NewSize(width-innerPadding*2, obj.MinSize().Height))
+			return
 		}
-		if row > 0 {
-			y += table[row-1][0].Height + padding
+		obj.Move(fyne.NewPos(x, y))
+		obj.Resize(fyne.NewSize(width, height))
+	}
+
+	y := float32(0)
+	for r, row := range rows {
+		if r > 0 {
+			y += padding
 		}
 
-		tableRow := table[row]
-		if _, ok := objects[i].(*canvas.Text); ok {
-			objects[i].Move(fyne.NewPos(innerPadding, y+innerPadding))
-			objects[i].Resize(fyne.NewSize(labelWidth-innerPadding*2, objects[i].MinSize().Height))
-		} else {
-			objects[i].Move(fyne.NewPos(0, y))
-			objects[i].Resize(fyne.NewSize(labelWidth, tableRow[0].Height))
+		if row.header != nil {
+			row.header.Move(fyne.NewPos(innerPadding, y+innerPadding))
+			row.header.Resize(fyne.NewSize(size.Width-innerPadding*2, row.header.MinSize().Height))
+			y += rowHeights[r]
+			continue
 		}
 
-		if i+1 < len(objects) {
-			if _, ok := objects[i+1].(*canvas.Text); ok {
-				objects[i+1].Move(fyne.NewPos(padding+labelWidth+innerPadding, y+innerPadding))
-				objects[i+1].Resize(fyne.NewSize(contentWidth-innerPadding*2, objects[i+1].MinSize().Height))
-			} else {
-				objects[i+1].Move(fyne.NewPos(padding+labelWidth, y))
-				objects[i+1].Resize(fyne.NewSize(contentWidth, tableRow[0].Height))
+		x := float32(0)
+		for col, pair := range row.pairs {
+			if col > 0 {
+				x += padding
 			}
+			placeCell(pair[0], x, y, labelWidths[col], rowHeights[r])
+			x += labelWidths[col] + padding
+			placeCell(pair[1], x, y, contentWidths[col], rowHeights[r])
+			x += contentWidths[col]
 		}
-		row++
+		y += rowHeights[r]
 	}
 }
 
 // MinSize finds the smallest size that satisfies all the child objects.
-// For a FormLayout this is the width of the widest label and content items and the height is
-// the sum of all column children combined with padding between each.
 func (f *formLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
-	labelWidth, contentWidth, table := f.tableCellsSize(objects, 0)
+	rows, rowHeights, labelWidths, contentWidths := f.measure(objects, 0)
 
 	minSize := fyne.NewSize(0, 0)
-	if len(table) == 0 {
+	if len(rows) == 0 {
 		return minSize
 	}
 
 	padding := theme.Padding()
-	added := false
-	minSize.Width = labelWidth + contentWidth + padding
-	for row := 0; row < len(table); row++ {
-		minSize.Height += table[row][0].Height
-		if added {
+	innerPadding := theme.InnerPadding()
+	for col := 0; col < f.pairsPerRow; col++ {
+		if col > 0 {
+			minSize.Width += padding
+		}
+		minSize.Width += labelWidths[col] + padding + contentWidths[col]
+	}
+
+	for r := range rows {
+		if r > 0 {
 			minSize.Height += padding
 		}
-		added = true
+		minSize.Height += rowHeights[r]
+
+		if rows[r].header != nil {
+			// Layout insets the header by innerPadding on each side, so the form's
+			// MinSize must include that or a header sized to exactly MinSize() would be
+			// clipped.
+			minSize.Width = fyne.Max(minSize.Width, rows[r].header.MinSize().Width+innerPadding*2)
+		}
 	}
+
 	return minSize
 }
 
-// NewFormLayout returns a new FormLayout instance
+// NewFormLayout returns a new FormLayout instance, a two column grid where each row has a
+// label and a widget.
 func NewFormLayout() fyne.Layout {
-	return &formLayout{}
+	return &formLayout{pairsPerRow: 1}
+}
+
+// NewFormLayoutWithColumns returns a form layout that places pairs label/content pairs side
+// by side on each row, so a wide window can show several fields per line.
+//
+// Since: 2.6
+func NewFormLayoutWithColumns(pairs int) fyne.Layout {
+	if pairs < 1 {
+		pairs = 1
+	}
+	return &formLayout{pairsPerRow: pairs}
+}
+
+// NewSectionedFormLayout returns a form layout with one label/content pair per row that additionally recognises
+// objects created with NewFormSectionHeader, rendering them with a divider and heading
+// style spanning the whole width of the table so long settings dialogs can be grouped.
+//
+// Since: 2.6
+func NewSectionedFormLayout() fyne.Layout {
+	return &formLayout{pairsPerRow: 1, sectioned: true}
 }
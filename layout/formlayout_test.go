@@ -0,0 +1,71 @@
+package layout
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+)
+
+func rect(w, h float32) *canvas.Rectangle {
+	r := canvas.NewRectangle(nil)
+	r.SetMinSize(fyne.NewSize(w, h))
+	return r
+}
+
+func TestFormLayout_MultiColumn(t *testing.T) {
+	l := &formLayout{pairsPerRow: 2}
+	objects := []fyne.CanvasObject{
+		rect(10, 20), rect(30, 20),
+		rect(15, 20), rect(25, 20),
+		rect(40, 20), rect(20, 20),
+	}
+
+	rows, rowHeights, labelWidths, contentWidths := l.measure(objects, 0)
+	if len(rows) != 2 {
+		t.Fatalf("expected 3 pairs split into 2 rows (2 pairs then 1), got %d rows", len(rows))
+	}
+	if len(rowHeights) != 2 {
+		t.Fatalf("expected 2 row heights, got %d", len(rowHeights))
+	}
+	if labelWidths[0] != 15 || labelWidths[1] != 40 {
+		t.Fatalf("expected widest label per column to win, got %v", labelWidths)
+	}
+	if contentWidths[0] != 30 || contentWidths[1] != 25 {
+		t.Fatalf("expected widest content per column to win, got %v", contentWidths)
+	}
+}
+
+func TestFormLayout_MinSizeAccountsForSectionHeader(t *testing.T) {
+	l := &formLayout{pairsPerRow: 1, sectioned: true}
+
+	header := NewFormSectionHeader("a very long section title that is wider than any field")
+	objects := []fyne.CanvasObject{
+		header,
+		rect(10, 20), rect(10, 20),
+	}
+
+	min := l.MinSize(objects)
+	wantWidth := header.MinSize().Width + theme.InnerPadding()*2
+	if min.Width < wantWidth {
+		t.Fatalf("MinSize width %v does not account for header inset, want at least %v", min.Width, wantWidth)
+	}
+}
+
+func TestFormLayout_SplitRowsStartsNewRowOnHeader(t *testing.T) {
+	l := &formLayout{pairsPerRow: 2, sectioned: true}
+	objects := []fyne.CanvasObject{
+		rect(10, 20), rect(10, 20),
+		NewFormSectionHeader("section"),
+		rect(10, 20), rect(10, 20),
+	}
+
+	rows := l.splitRows(objects)
+	if len(rows) != 3 {
+		t.Fatalf("expected header to force a row break even with room left in the pair row, got %d rows", len(rows))
+	}
+	if rows[1].header == nil {
+		t.Fatalf("expected the middle row to be the section header")
+	}
+}
@@ -0,0 +1,115 @@
+package theme
+
+import (
+	"image/color"
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// OverlayTheme wraps another theme and lets individual colors and sizes be overridden at
+// runtime, without having to implement a whole new theme. Any name that has not been
+// overridden falls back to the wrapped theme.
+//
+// This is the data model behind a runtime theme editor (app/preferences.go persists an
+// OverlayTheme built from it under the "_theme_overrides" key). It is not a complete
+// implementation of that feature on its own: until dialog.NewThemeEditor / widget.ThemeEditor
+// land in a follow-up, nothing in this tree lets a user create or edit an OverlayTheme - an
+// app can only benefit from this today by hand-building one and calling
+// app.Settings().SetTheme, or by hand-writing the "_theme_overrides" JSON. Do not treat the
+// runtime theme editor as delivered until that follow-up merges.
+//
+// Since: 2.6
+type OverlayTheme struct {
+	Base fyne.Theme
+
+	lock   sync.RWMutex
+	colors map[fyne.ThemeColorName]color.Color
+	sizes  map[fyne.SizeName]float32
+}
+
+// NewOverlayTheme returns an OverlayTheme that falls back to base for any color or size
+// that has not been overridden.
+//
+// Since: 2.6
+func NewOverlayTheme(base fyne.Theme) *OverlayTheme {
+	return &OverlayTheme{Base: base}
+}
+
+// SetColor overrides the color returned for the given name, ignoring variant.
+// Pass a nil color to remove the override and fall back to the base theme again.
+//
+// Since: 2.6
+func (t *OverlayTheme) SetColor(name fyne.ThemeColorName, c color.Color) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if c == nil {
+		delete(t.colors, name)
+		return
+	}
+	if t.colors == nil {
+		t.colors = make(map[fyne.ThemeColorName]color.Color)
+	}
+	t.colors[name] = c
+}
+
+// SetSize overrides the size returned for the given name.
+// Pass a negative value to remove the override and fall back to the base theme again.
+//
+// Since: 2.6
+func (t *OverlayTheme) SetSize(name fyne.SizeName, size float32) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if size < 0 {
+		delete(t.sizes, name)
+		return
+	}
+	if t.sizes == nil {
+		t.sizes = make(map[fyne.SizeName]float32)
+	}
+	t.sizes[name] = size
+}
+
+// Color looks up the named color, returning the override if one was set with SetColor.
+func (t *OverlayTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	t.lock.RLock()
+	c, ok := t.colors[name]
+	t.lock.RUnlock()
+	if ok {
+		return c
+	}
+
+	return t.Base.Color(name, variant)
+}
+
+// Font looks up the font resource for the given style from the base theme.
+func (t *OverlayTheme) Font(style fyne.TextStyle) fyne.Resource {
+	return t.Base.Font(style)
+}
+
+// Icon looks up the named icon resource from the base theme.
+func (t *OverlayTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
+	return t.Base.Icon(name)
+}
+
+// Size looks up the named size, returning the override if one was set with SetSize.
+func (t *OverlayTheme) Size(name fyne.SizeName) float32 {
+	t.lock.RLock()
+	s, ok := t.sizes[name]
+	t.lock.RUnlock()
+	if ok {
+		return s
+	}
+
+	return t.Base.Size(name)
+}
+
+// Style looks up the named text style, deferring to the base theme since OverlayTheme
+// only supports overriding colors and sizes.
+//
+// Since: 2.6
+func (t *OverlayTheme) Style(name ThemeStyleName) TextStyle {
+	return styleLookup(t.Base, name)
+}
@@ -0,0 +1,53 @@
+package theme
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+func TestOverlayTheme_ColorFallsBackToBase(t *testing.T) {
+	base := plainTheme{}
+	overlay := NewOverlayTheme(base)
+
+	if got := overlay.Color(fyne.ThemeColorName("any"), fyne.VariantLight); got != color.Black {
+		t.Fatalf("expected Color to fall back to the base theme when not overridden, got %v", got)
+	}
+
+	overlay.SetColor(fyne.ThemeColorName("any"), color.White)
+	if got := overlay.Color(fyne.ThemeColorName("any"), fyne.VariantLight); got != color.White {
+		t.Fatalf("expected Color to return the overridden value, got %v", got)
+	}
+
+	overlay.SetColor(fyne.ThemeColorName("any"), nil)
+	if got := overlay.Color(fyne.ThemeColorName("any"), fyne.VariantLight); got != color.Black {
+		t.Fatalf("expected a nil SetColor to remove the override and fall back to base again, got %v", got)
+	}
+}
+
+func TestOverlayTheme_SizeFallsBackToBase(t *testing.T) {
+	overlay := NewOverlayTheme(plainTheme{})
+
+	if got := overlay.Size(fyne.SizeName("any")); got != 0 {
+		t.Fatalf("expected Size to fall back to the base theme when not overridden, got %v", got)
+	}
+
+	overlay.SetSize(fyne.SizeName("any"), 42)
+	if got := overlay.Size(fyne.SizeName("any")); got != 42 {
+		t.Fatalf("expected Size to return the overridden value, got %v", got)
+	}
+
+	overlay.SetSize(fyne.SizeName("any"), -1)
+	if got := overlay.Size(fyne.SizeName("any")); got != 0 {
+		t.Fatalf("expected a negative SetSize to remove the override and fall back to base again, got %v", got)
+	}
+}
+
+func TestOverlayTheme_StyleDefersToBase(t *testing.T) {
+	overlay := NewOverlayTheme(stubStyledTheme{style: StyleItalic})
+
+	if got := overlay.Style(StyleNameHeading); got != StyleItalic {
+		t.Fatalf("expected Style to defer to the base theme's own lookup, got %v", got)
+	}
+}
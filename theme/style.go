@@ -0,0 +1,128 @@
+package theme
+
+import "fyne.io/fyne/v2"
+
+// ThemeStyleName is used to look up a text style from a theme.
+// Use with the Style and StyleForWidget functions.
+//
+// Since: 2.6
+type ThemeStyleName string
+
+const (
+	// StyleNameHyperlink is the name of the theme lookup for the style of hyperlink text.
+	//
+	// Since: 2.6
+	StyleNameHyperlink ThemeStyleName = "hyperlink"
+
+	// StyleNameHeading is the name of the theme lookup for the style of heading text.
+	//
+	// Since: 2.6
+	StyleNameHeading ThemeStyleName = "heading"
+
+	// StyleNameError is the name of the theme lookup for the style of error text.
+	//
+	// Since: 2.6
+	StyleNameError ThemeStyleName = "error"
+
+	// StyleNamePlaceHolder is the name of the theme lookup for the style of placeholder text.
+	//
+	// Since: 2.6
+	StyleNamePlaceHolder ThemeStyleName = "placeholder"
+)
+
+// TextStyle is a bitmask of typographic attributes that can be looked up alongside a theme
+// colour, such as bold or italic.
+//
+// Since: 2.6
+type TextStyle uint8
+
+const (
+	// StyleRegular applies no typographic attribute.
+	//
+	// Since: 2.6
+	StyleRegular TextStyle = 0
+
+	// StyleBold makes text bold.
+	//
+	// Since: 2.6
+	StyleBold TextStyle = 1 << (iota - 1)
+	// StyleItalic makes text italic.
+	//
+	// Since: 2.6
+	StyleItalic
+	// StyleUnderline draws a line beneath the text.
+	//
+	// Since: 2.6
+	StyleUnderline
+	// StyleStrikethrough draws a line through the text.
+	//
+	// Since: 2.6
+	StyleStrikethrough
+	// StyleReverse swaps the text and background colors.
+	//
+	// Since: 2.6
+	StyleReverse
+	// StyleBlink causes the text to blink, where the renderer supports it.
+	//
+	// Since: 2.6
+	StyleBlink
+	// StyleDim renders the text at a reduced intensity.
+	//
+	// Since: 2.6
+	StyleDim
+)
+
+// styledTheme is implemented by themes that support the extended text style lookup.
+// It is kept separate from the Theme interface so that existing themes do not need to
+// be updated to remain valid implementations.
+type styledTheme interface {
+	Style(name ThemeStyleName) TextStyle
+}
+
+// Style looks up the named text style for the current theme.
+// Themes that do not implement the extended style lookup use the built-in defaults:
+// underline for hyperlinks, bold for headings and errors, and StyleRegular otherwise.
+//
+// Since: 2.6
+func Style(name ThemeStyleName) TextStyle {
+	return styleLookup(Current(), name)
+}
+
+// StyleForWidget looks up the named text style for the requested widget using the current theme.
+// If the widget theme has been overridden that theme will be used.
+//
+// Since: 2.6
+func StyleForWidget(name ThemeStyleName, w fyne.Widget) TextStyle {
+	return styleLookup(CurrentForWidget(w), name)
+}
+
+func styleLookup(th fyne.Theme, name ThemeStyleName) TextStyle {
+	if styled, ok := th.(styledTheme); ok {
+		return styled.Style(name)
+	}
+
+	return defaultStyleFor(name)
+}
+
+// defaultStyleFor provides sensible styling for the built-in style names when a theme
+// does not implement styledTheme.
+//
+// Note: no widget in this tree has been retrofitted yet to read Style/StyleForWidget
+// instead of hard-coding its own fyne.TextStyle (Hyperlink, RichText, form labels and error
+// text are the intended first consumers) - the widget package isn't part of this checkout.
+// Until that retrofit lands, Style/StyleForWidget have no observable effect anywhere in the
+// product; do not treat the text-style attribute system as delivered until it does.
+func defaultStyleFor(name ThemeStyleName) TextStyle {
+	switch name {
+	case StyleNameHyperlink:
+		return StyleUnderline
+	case StyleNameHeading:
+		return StyleBold
+	case StyleNameError:
+		return StyleBold
+	case StyleNamePlaceHolder:
+		return StyleRegular
+	}
+
+	return StyleRegular
+}
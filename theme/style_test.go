@@ -0,0 +1,54 @@
+package theme
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+// plainTheme is a minimal fyne.Theme that does not implement styledTheme, used to check that
+// styleLookup falls back to defaultStyleFor for themes with no extended style support.
+type plainTheme struct{}
+
+func (plainTheme) Color(fyne.ThemeColorName, fyne.ThemeVariant) color.Color { return color.Black }
+func (plainTheme) Font(fyne.TextStyle) fyne.Resource                       { return nil }
+func (plainTheme) Icon(fyne.ThemeIconName) fyne.Resource                   { return nil }
+func (plainTheme) Size(fyne.SizeName) float32                              { return 0 }
+
+func TestDefaultStyleFor(t *testing.T) {
+	cases := map[ThemeStyleName]TextStyle{
+		StyleNameHyperlink:           StyleUnderline,
+		StyleNameHeading:             StyleBold,
+		StyleNameError:               StyleBold,
+		StyleNamePlaceHolder:         StyleRegular,
+		ThemeStyleName("not-a-name"): StyleRegular,
+	}
+
+	for name, want := range cases {
+		if got := defaultStyleFor(name); got != want {
+			t.Errorf("defaultStyleFor(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// stubStyledTheme is a minimal styledTheme used to check that styleLookup defers to a theme
+// that implements the extended lookup instead of always using defaultStyleFor.
+type stubStyledTheme struct {
+	plainTheme
+	style TextStyle
+}
+
+func (s stubStyledTheme) Style(ThemeStyleName) TextStyle {
+	return s.style
+}
+
+func TestStyleLookup(t *testing.T) {
+	if got := styleLookup(stubStyledTheme{style: StyleItalic}, StyleNameHeading); got != StyleItalic {
+		t.Errorf("styleLookup should defer to a styledTheme's own Style method, got %v", got)
+	}
+
+	if got := styleLookup(plainTheme{}, StyleNameHeading); got != StyleBold {
+		t.Errorf("styleLookup should fall back to defaultStyleFor for a theme that doesn't implement styledTheme, got %v", got)
+	}
+}